@@ -1,39 +1,122 @@
 package alm
 
 import (
+	"context"
+	"strings"
 	"time"
 
 	"fmt"
 
-	"github.com/coreos-inc/alm/operators"
+	"github.com/coreos-inc/alm/install"
 	"github.com/coreos-inc/operator-client/pkg/client"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
 const (
-	defaultQPS   = 100
-	defaultBurst = 100
+	// defaultWorkers is how many workers Run starts when New wasn't given a positive count.
+	defaultWorkers = 1
+
+	// syncTimeout bounds how long a single OperatorVersion's sync may run before its
+	// context is cancelled, so a stuck install can't block a worker forever.
+	syncTimeout = 5 * time.Minute
+
+	// minRetryDelay and maxRetryDelay bound the per-item exponential backoff applied when
+	// a sync fails and is re-queued.
+	minRetryDelay = 5 * time.Millisecond
+	maxRetryDelay = 1000 * time.Second
+
+	// installPollInterval is how soon sync re-checks an OperatorVersion still in the
+	// Installing phase, so readiness is observed promptly instead of waiting for the
+	// informer's 15-minute resync.
+	installPollInterval = 5 * time.Second
+
+	// queueQPS and queueBurst bound the overall rate at which items leave the queue across
+	// all keys, on top of each key's own backoff, so a thundering herd of failures can't
+	// hammer the API server.
+	queueQPS   = 50
+	queueBurst = 300
 )
 
 type Operator struct {
-	queue    workqueue.RateLimitingInterface
+	queue    workqueue.TypedRateLimitingInterface[cache.ObjectName]
 	informer cache.SharedIndexInformer
 	opClient client.Interface
+	// operatorVersionClient is scoped to operatorVersionGroupVersion (see restclient.go),
+	// unlike opClient.KubernetesInterface(), which only talks to the built-in Kubernetes
+	// APIs; the finalizer and status writes in finalizer.go/status.go need the former.
+	operatorVersionClient rest.Interface
+	strategies            *install.Registry
+	workers               int
+	leaderElection        LeaderElectionConfig
+}
+
+// errPendingDependencies is returned by sync when an OperatorVersion's Requires aren't yet
+// satisfied. It's a normal, transient state rather than a failure, so processNextWorkItem
+// re-enqueues it through the rate limiter without reporting it via utilruntime.HandleError.
+type errPendingDependencies struct {
+	missing []string
+}
+
+func (e *errPendingDependencies) Error() string {
+	return fmt.Sprintf("waiting on unresolved dependencies: %s", strings.Join(e.missing, ", "))
+}
+
+// defaultStrategies returns the registry of install strategies ALM ships with. Third
+// parties can register additional strategies into the same Registry before it's handed
+// to an Operator.
+func defaultStrategies(opClient client.Interface) *install.Registry {
+	registry := install.NewRegistry()
+	registry.Register("deployment", install.NewDeploymentStrategy(opClient))
+	registry.Register("helm", install.NewHelmStrategy(opClient))
+	registry.Register("job", install.NewJobStrategy(opClient))
+	return registry
 }
 
-func New(kubeconfig string) (*Operator, error) {
+// rateLimiter combines a per-item exponential backoff with a global token bucket, so a
+// single misbehaving OperatorVersion backs off on its own while the queue as a whole never
+// exceeds queueQPS/queueBurst requests against the API server.
+func rateLimiter() workqueue.TypedRateLimiter[cache.ObjectName] {
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[cache.ObjectName](minRetryDelay, maxRetryDelay),
+		&workqueue.TypedBucketRateLimiter[cache.ObjectName]{Limiter: rate.NewLimiter(rate.Limit(queueQPS), queueBurst)},
+	)
+}
+
+// New returns an Operator that reconciles OperatorVersions using workers concurrent
+// goroutines once Run is called. A non-positive workers defaults to defaultWorkers. When
+// leConfig.Enabled is set, Run only starts the informer and workers while this Operator
+// holds the configured leader election lease, so multiple replicas can run HA.
+func New(kubeconfig string, workers int, leConfig LeaderElectionConfig) (*Operator, error) {
 	client := client.NewClient(kubeconfig)
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	operatorVersionClient, err := newOperatorVersionClient(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "building operatorversions REST client")
+	}
 
 	operator := &Operator{
-		opClient: client,
+		opClient:              client,
+		operatorVersionClient: operatorVersionClient,
+		strategies:            defaultStrategies(client),
+		workers:               workers,
+		leaderElection:        withLeaderElectionDefaults(leConfig),
 	}
-	operator.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "alm")
+	operator.queue = workqueue.NewTypedRateLimitingQueueWithConfig(
+		rateLimiter(),
+		workqueue.TypedRateLimitingQueueConfig[cache.ObjectName]{Name: "alm"},
+	)
 	operatorVersionWatcher := cache.NewListWatchFromClient(
 		client.KubernetesInterface().CoreV1().RESTClient(),
 		"operatorversions",
@@ -47,12 +130,18 @@ func New(kubeconfig string) (*Operator, error) {
 		cache.Indexers{},
 	)
 	operator.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: operator.handleAddOperatorVersion,
+		AddFunc:    operator.handleAddOperatorVersion,
+		UpdateFunc: operator.handleUpdateOperatorVersion,
+		DeleteFunc: operator.handleDeleteOperatorVersion,
 	})
 	return operator, nil
 }
 
-func (o *Operator) Run(stopc <-chan struct{}) error {
+// Run blocks until ctx is cancelled (or, with leader election enabled, until this replica
+// loses the lease), reconciling OperatorVersions using o.workers
+// goroutines in the meantime. The workqueue still guarantees that no two workers process
+// the same OperatorVersion concurrently.
+func (o *Operator) Run(ctx context.Context) error {
 	defer o.queue.ShutDown()
 
 	errChan := make(chan error)
@@ -72,107 +161,221 @@ func (o *Operator) Run(stopc <-chan struct{}) error {
 			return err
 		}
 		log.Info("msg", "Operator ready")
-	case <-stopc:
+	case <-ctx.Done():
+		return nil
+	}
+
+	if !o.leaderElection.Enabled {
+		o.runWorkers(ctx)
+		<-ctx.Done()
 		return nil
 	}
 
-	go o.worker()
-	go o.informer.Run(stopc)
+	return o.runWithLeaderElection(ctx)
+}
 
-	<-stopc
-	return nil
+// runWorkers starts the informer and o.workers reconciler goroutines, returning once
+// they've been launched. They keep running until ctx is cancelled.
+func (o *Operator) runWorkers(ctx context.Context) {
+	for i := 0; i < o.workers; i++ {
+		go o.worker(ctx)
+	}
+	go o.informer.Run(ctx.Done())
 }
 
-func (o *Operator) keyFunc(obj interface{}) (string, bool) {
-	k, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+func (o *Operator) keyFunc(obj interface{}) (cache.ObjectName, bool) {
+	name, err := cache.DeletionHandlingObjectToName(obj)
 	if err != nil {
 		log.Info("msg", "creating key failed", "err", err)
-		return k, false
+		return cache.ObjectName{}, false
 	}
 
-	return k, true
+	return name, true
 }
 
-// enqueue adds a key to the queue. If obj is a key already it gets added directly.
-// Otherwise, the key is extracted via keyFunc.
-func (o *Operator) enqueue(obj interface{}) {
-	if obj == nil {
-		return
-	}
-
-	key, ok := obj.(string)
-	if !ok {
-		key, ok = o.keyFunc(obj)
-		if !ok {
-			return
-		}
-	}
-
-	o.queue.Add(key)
+// enqueue adds name to the queue.
+func (o *Operator) enqueue(name cache.ObjectName) {
+	o.queue.Add(name)
 }
 
 // worker runs a worker thread that just dequeues items, processes them, and marks them done.
 // It enforces that the syncHandler is never invoked concurrently with the same key.
-func (c *Operator) worker() {
-	for c.processNextWorkItem() {
+func (c *Operator) worker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
-func (o *Operator) processNextWorkItem() bool {
-	key, quit := o.queue.Get()
+func (o *Operator) processNextWorkItem(ctx context.Context) bool {
+	name, quit := o.queue.Get()
 	if quit {
 		return false
 	}
-	defer o.queue.Done(key)
+	defer o.queue.Done(name)
 
-	err := o.sync(key.(string))
+	itemCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+
+	err := o.sync(itemCtx, name)
 	if err == nil {
-		o.queue.Forget(key)
+		o.queue.Forget(name)
 		return true
 	}
 
-	utilruntime.HandleError(errors.Wrap(err, fmt.Sprintf("Sync %q failed", key)))
-	o.queue.AddRateLimited(key)
+	if pending, ok := err.(*errPendingDependencies); ok {
+		// Waiting on dependencies is an expected, transient state, not a failure; don't
+		// route it through HandleError, but do keep backing off via the rate limiter so a
+		// long-unresolved OperatorVersion doesn't busy-loop the queue.
+		log.Info("msg", "sync waiting on dependencies", "key", name, "missing", pending.missing)
+		o.queue.AddRateLimited(name)
+		return true
+	}
+
+	utilruntime.HandleError(errors.Wrap(err, fmt.Sprintf("Sync %q failed", name)))
+	o.queue.AddRateLimited(name)
 
 	return true
 }
 
-func (o *Operator) sync(key string) error {
-	obj, exists, err := o.informer.GetIndexer().GetByKey(key)
+func (o *Operator) sync(ctx context.Context, name cache.ObjectName) error {
+	obj, exists, err := o.informer.GetIndexer().GetByKey(name.String())
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		// For now, we ignore the case where an OperatorVersionSpec used to exist but no longer does
+		// The OperatorVersion is gone from the cache, meaning the API server already
+		// finished deleting it (our finalizer having been removed in an earlier sync).
+		// There's nothing left to garbage collect.
 		return nil
 	}
 
-	operatorVersion, ok := obj.(*OperatorVersion)
+	cached, ok := obj.(*OperatorVersion)
 	if !ok {
 		return fmt.Errorf("casting OperatorVersionSpec failed")
 	}
+	// cached is the shared informer-cache entry; sync mutates finalizers and status on its
+	// way through, so it must work on its own copy rather than corrupting what other
+	// workers and the resolver read concurrently.
+	operatorVersion := cached.DeepCopyObject().(*OperatorVersion)
+
+	if operatorVersion.ObjectMeta.DeletionTimestamp != nil {
+		log.Info("msg", "finalizing OperatorVersionSpec", "key", name)
+		return o.finalize(ctx, operatorVersion)
+	}
 
-	log.Info("msg", "sync OperatorVersionSpec", "key", key)
-	install := operatorVersion.Spec.InstallStrategy.UnstructuredContent()
-	strategy := install["strategy"]
-	strategyString, ok := strategy.(string)
+	if err := o.ensureFinalizer(ctx, operatorVersion); err != nil {
+		return errors.Wrap(err, "adding finalizer")
+	}
+
+	log.Info("msg", "sync OperatorVersionSpec", "key", name)
+
+	resolved, err := o.resolveDependencies(operatorVersion)
+	if err != nil {
+		if patchErr := o.setResolvedCondition(ctx, operatorVersion, PhaseFailed, corev1.ConditionFalse, "DependencyCycle", err.Error()); patchErr != nil {
+			utilruntime.HandleError(errors.Wrap(patchErr, "updating OperatorVersion status"))
+		}
+		// A cycle can't resolve itself by retrying; surface it and wait for the spec to change.
+		return nil
+	}
+	if !resolved.satisfied {
+		if err := o.setResolvedCondition(ctx, operatorVersion, PhasePending, corev1.ConditionFalse, "WaitingForDependencies", "missing: "+strings.Join(resolved.missing, ", ")); err != nil {
+			utilruntime.HandleError(errors.Wrap(err, "updating OperatorVersion status"))
+		}
+		return &errPendingDependencies{missing: resolved.missing}
+	}
+	// The Resolved condition itself is folded into reconcileStatus's single patch below,
+	// rather than PATCHed here on its own: a standalone write here would carry
+	// operatorVersion.Status.Phase as it stood before this sync (empty, on a first
+	// install) and be immediately overwritten by reconcileStatus's Phase.
+
+	strategyName, err := installStrategyName(operatorVersion)
+	if err != nil {
+		return errors.Wrap(err, "determining install strategy")
+	}
+	strategy, ok := o.strategies.Get(strategyName)
 	if !ok {
-		return fmt.Errorf("casting strategy failed")
+		return fmt.Errorf("no install strategy registered for %q", strategyName)
+	}
+
+	installed, err := strategy.CheckInstalled(ctx, operatorVersion.ObjectMeta.Namespace, operatorVersion.Spec.InstallStrategy)
+	if err != nil {
+		return errors.Wrapf(err, "checking whether OperatorVersion %q is already installed", name)
 	}
-	if strategyString == "deployment" {
-		kubeDeployment := alm.NewKubeDeployment(o.opClient)
-		kubeDeployment.Install(operatorVersion.ObjectMeta.Namespace, install["deployments"])
+	var installErr error
+	if !installed {
+		// Only Install when CheckInstalled doesn't already report the resources present and
+		// ready. Every strategy's Install leaves objects that already exist as they are, so
+		// running it again on the next poll while they're still becoming ready is harmless.
+		installErr = strategy.Install(ctx, operatorVersion.ObjectMeta.Namespace, ownerRef(operatorVersion), operatorVersion.Spec.InstallStrategy)
+	}
+	if err := o.reconcileStatus(ctx, operatorVersion, strategy, installErr); err != nil {
+		// Don't let a failed status write mask the install result, but do surface it.
+		utilruntime.HandleError(errors.Wrap(err, "updating OperatorVersion status"))
+	}
+	if installErr != nil {
+		return errors.Wrapf(installErr, "installing OperatorVersion %q via %q strategy", name, strategyName)
+	}
+	if operatorVersion.Status.Phase == PhaseInstalling {
+		// Install succeeded but the resources it created aren't ready yet; poll again
+		// shortly rather than sticking here until the informer's next resync.
+		o.queue.AddAfter(name, installPollInterval)
 	}
 
 	return nil
 }
 
+// installStrategyName extracts the install strategy name ov requests.
+func installStrategyName(ov *OperatorVersion) (string, error) {
+	return install.Name(ov.Spec.InstallStrategy)
+}
+
 func (o *Operator) handleAddOperatorVersion(obj interface{}) {
-	key, ok := o.keyFunc(obj)
+	name, ok := o.keyFunc(obj)
+	if !ok {
+		return
+	}
+	log.Info("msg", "OperatorVersionSpec added", "key", name)
+	o.enqueue(name)
+}
+
+func (o *Operator) handleUpdateOperatorVersion(oldObj, newObj interface{}) {
+	if oldOV, ok := oldObj.(*OperatorVersion); ok {
+		if newOV, ok := newObj.(*OperatorVersion); ok &&
+			oldOV.ObjectMeta.ResourceVersion != newOV.ObjectMeta.ResourceVersion &&
+			!operatorVersionNeedsSync(oldOV, newOV) {
+			// Our own finalizer PUT and status PATCH land here too; re-enqueueing on those
+			// would immediately undo sync's "already installed?" guard and fire Install
+			// again on every self-write instead of only when something we don't control
+			// about the OperatorVersion actually changed. The informer's periodic resync
+			// redelivers an unchanged ResourceVersion and is still enqueued.
+			return
+		}
+	}
+
+	name, ok := o.keyFunc(newObj)
+	if !ok {
+		return
+	}
+	log.Info("msg", "OperatorVersionSpec updated", "key", name)
+	o.enqueue(name)
+}
+
+// operatorVersionNeedsSync reports whether newOV differs from oldOV in a way sync cares
+// about. Spec changes bump ObjectMeta.Generation; a delete request sets DeletionTimestamp
+// without touching Generation. Everything else the controller itself writes back
+// (Finalizers, Status, ResourceVersion) is deliberately not compared here.
+func operatorVersionNeedsSync(oldOV, newOV *OperatorVersion) bool {
+	if oldOV.ObjectMeta.Generation != newOV.ObjectMeta.Generation {
+		return true
+	}
+	return (oldOV.ObjectMeta.DeletionTimestamp == nil) != (newOV.ObjectMeta.DeletionTimestamp == nil)
+}
+
+func (o *Operator) handleDeleteOperatorVersion(obj interface{}) {
+	name, ok := o.keyFunc(obj)
 	if !ok {
 		return
 	}
-	log.Info("msg", "OperatorVersionSpec added", "key", key)
-	o.enqueue(key)
+	log.Info("msg", "OperatorVersionSpec deleted", "key", name)
+	o.enqueue(name)
 }
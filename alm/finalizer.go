@@ -0,0 +1,77 @@
+package alm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// operatorVersionFinalizer is added to every OperatorVersion we've started installing, and
+// removed once its child resources have been torn down. Its presence keeps the API server
+// from deleting the OperatorVersion out from under us, so a partial delete is retried
+// through the workqueue instead of silently dropped.
+const operatorVersionFinalizer = "alm.coreos.com/installer"
+
+func hasFinalizer(ov *OperatorVersion, name string) bool {
+	for _, f := range ov.ObjectMeta.Finalizers {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureFinalizer adds operatorVersionFinalizer to ov if it isn't already present.
+func (o *Operator) ensureFinalizer(ctx context.Context, ov *OperatorVersion) error {
+	if hasFinalizer(ov, operatorVersionFinalizer) {
+		return nil
+	}
+	ov.ObjectMeta.Finalizers = append(ov.ObjectMeta.Finalizers, operatorVersionFinalizer)
+	return errors.Wrap(o.updateOperatorVersion(ctx, ov), "adding install finalizer")
+}
+
+// removeFinalizer drops operatorVersionFinalizer from ov once its child resources are gone.
+func (o *Operator) removeFinalizer(ctx context.Context, ov *OperatorVersion) error {
+	kept := ov.ObjectMeta.Finalizers[:0]
+	for _, f := range ov.ObjectMeta.Finalizers {
+		if f != operatorVersionFinalizer {
+			kept = append(kept, f)
+		}
+	}
+	ov.ObjectMeta.Finalizers = kept
+	return errors.Wrap(o.updateOperatorVersion(ctx, ov), "removing install finalizer")
+}
+
+// updateOperatorVersion persists ov's metadata (e.g. finalizers) back to the API server.
+func (o *Operator) updateOperatorVersion(ctx context.Context, ov *OperatorVersion) error {
+	return o.operatorVersionClient.
+		Put().
+		Namespace(ov.ObjectMeta.Namespace).
+		Resource("operatorversions").
+		Name(ov.ObjectMeta.Name).
+		Body(ov).
+		Do(ctx).
+		Error()
+}
+
+// finalize runs the install strategy's Uninstall to garbage-collect ov's child resources
+// and, once that succeeds, drops the finalizer so the API server can finish deleting it.
+func (o *Operator) finalize(ctx context.Context, ov *OperatorVersion) error {
+	if !hasFinalizer(ov, operatorVersionFinalizer) {
+		return nil
+	}
+
+	strategyName, err := installStrategyName(ov)
+	if err != nil {
+		return errors.Wrap(err, "determining install strategy for uninstall")
+	}
+	strategy, ok := o.strategies.Get(strategyName)
+	if !ok {
+		return errors.Errorf("no install strategy registered for %q", strategyName)
+	}
+	if err := strategy.Uninstall(ctx, ov.ObjectMeta.Namespace, ov.Spec.InstallStrategy); err != nil {
+		return errors.Wrapf(err, "uninstalling OperatorVersion %s/%s", ov.ObjectMeta.Namespace, ov.ObjectMeta.Name)
+	}
+
+	return o.removeFinalizer(ctx, ov)
+}
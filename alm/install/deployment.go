@@ -0,0 +1,64 @@
+package install
+
+import (
+	"context"
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/coreos-inc/alm/operators"
+	"github.com/coreos-inc/operator-client/pkg/client"
+	"github.com/pkg/errors"
+)
+
+// deploymentDoc is the InstallStrategy document understood by DeploymentStrategy:
+//
+//	{"strategy": "deployment", "deployments": [<appsv1.Deployment>, ...]}
+type deploymentDoc struct {
+	Deployments []appsv1.Deployment `json:"deployments"`
+}
+
+// DeploymentStrategy installs an operator by creating the Deployments listed in the
+// InstallStrategy document directly via the Kubernetes API.
+type DeploymentStrategy struct {
+	kubeDeployment *operators.KubeDeployment
+}
+
+// NewDeploymentStrategy returns a DeploymentStrategy that creates resources via opClient.
+func NewDeploymentStrategy(opClient client.Interface) *DeploymentStrategy {
+	return &DeploymentStrategy{kubeDeployment: operators.NewKubeDeployment(opClient)}
+}
+
+func (d *DeploymentStrategy) Install(ctx context.Context, namespace string, owner metav1.OwnerReference, spec runtime.RawExtension) error {
+	doc, err := parseDeploymentDoc(spec)
+	if err != nil {
+		return err
+	}
+	return d.kubeDeployment.Install(ctx, namespace, owner, doc.Deployments)
+}
+
+func (d *DeploymentStrategy) Uninstall(ctx context.Context, namespace string, spec runtime.RawExtension) error {
+	doc, err := parseDeploymentDoc(spec)
+	if err != nil {
+		return err
+	}
+	return d.kubeDeployment.Uninstall(ctx, namespace, doc.Deployments)
+}
+
+func (d *DeploymentStrategy) CheckInstalled(ctx context.Context, namespace string, spec runtime.RawExtension) (bool, error) {
+	doc, err := parseDeploymentDoc(spec)
+	if err != nil {
+		return false, err
+	}
+	return d.kubeDeployment.CheckInstalled(ctx, namespace, doc.Deployments)
+}
+
+func parseDeploymentDoc(spec runtime.RawExtension) (*deploymentDoc, error) {
+	doc := &deploymentDoc{}
+	if err := json.Unmarshal(spec.Raw, doc); err != nil {
+		return nil, errors.Wrap(err, "parsing deployment install strategy")
+	}
+	return doc, nil
+}
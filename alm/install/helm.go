@@ -0,0 +1,124 @@
+package install
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/renderutil"
+
+	"github.com/coreos-inc/alm/operators"
+	"github.com/coreos-inc/operator-client/pkg/client"
+	"github.com/pkg/errors"
+)
+
+// helmDoc is the InstallStrategy document understood by HelmStrategy:
+//
+//	{"strategy": "helm", "chart": "<base64-encoded chart .tgz>", "values": {...}}
+type helmDoc struct {
+	Chart  string                 `json:"chart"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// HelmStrategy installs an operator by rendering a chart bundled in the OperatorVersion
+// and applying the Deployments it produces. Only Deployment manifests in the rendered
+// output are applied; other kinds are ignored, matching what DeploymentStrategy supports.
+type HelmStrategy struct {
+	kubeDeployment *operators.KubeDeployment
+}
+
+// NewHelmStrategy returns a HelmStrategy that creates resources via opClient.
+func NewHelmStrategy(opClient client.Interface) *HelmStrategy {
+	return &HelmStrategy{kubeDeployment: operators.NewKubeDeployment(opClient)}
+}
+
+func (h *HelmStrategy) Install(ctx context.Context, namespace string, owner metav1.OwnerReference, spec runtime.RawExtension) error {
+	deployments, err := h.renderDeployments(namespace, spec)
+	if err != nil {
+		return err
+	}
+	return h.kubeDeployment.Install(ctx, namespace, owner, deployments)
+}
+
+func (h *HelmStrategy) Uninstall(ctx context.Context, namespace string, spec runtime.RawExtension) error {
+	deployments, err := h.renderDeployments(namespace, spec)
+	if err != nil {
+		return err
+	}
+	return h.kubeDeployment.Uninstall(ctx, namespace, deployments)
+}
+
+func (h *HelmStrategy) CheckInstalled(ctx context.Context, namespace string, spec runtime.RawExtension) (bool, error) {
+	deployments, err := h.renderDeployments(namespace, spec)
+	if err != nil {
+		return false, err
+	}
+	return h.kubeDeployment.CheckInstalled(ctx, namespace, deployments)
+}
+
+// renderDeployments decodes the bundled chart, renders it with the given values, and
+// extracts the Deployment manifests from the rendered output.
+func (h *HelmStrategy) renderDeployments(namespace string, spec runtime.RawExtension) ([]appsv1.Deployment, error) {
+	doc := &helmDoc{}
+	if err := json.Unmarshal(spec.Raw, doc); err != nil {
+		return nil, errors.Wrap(err, "parsing helm install strategy")
+	}
+
+	chartBytes, err := base64.StdEncoding.DecodeString(doc.Chart)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding bundled chart")
+	}
+	chart, err := chartutil.LoadArchive(bytes.NewReader(chartBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "loading bundled chart")
+	}
+
+	valuesJSON, err := toJSON(doc.Values)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding chart values")
+	}
+	values, err := chartutil.ToRenderValuesCaps(chart, &chartutil.Config{Raw: valuesJSON}, chartutil.ReleaseOptions{Namespace: namespace}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "computing chart values")
+	}
+	rendered, err := renderutil.Render(chart, values, renderutil.Options{})
+	if err != nil {
+		return nil, errors.Wrap(err, "rendering chart")
+	}
+
+	decoder := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
+	var deployments []appsv1.Deployment
+	for _, manifest := range rendered {
+		obj, _, err := decoder.Decode([]byte(manifest), nil, nil)
+		if err != nil {
+			// Not every rendered file is a Kubernetes manifest (e.g. NOTES.txt); skip it.
+			continue
+		}
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || u.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &deployment); err != nil {
+			return nil, errors.Wrap(err, "converting rendered Deployment")
+		}
+		deployments = append(deployments, deployment)
+	}
+	return deployments, nil
+}
+
+// toJSON encodes values as JSON, which chartutil.Config.Raw also accepts as valid YAML.
+func toJSON(values map[string]interface{}) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,84 @@
+package install
+
+import (
+	"context"
+	"encoding/json"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/coreos-inc/operator-client/pkg/client"
+	"github.com/pkg/errors"
+)
+
+// jobDoc is the InstallStrategy document understood by JobStrategy:
+//
+//	{"strategy": "job", "job": <batchv1.Job>}
+type jobDoc struct {
+	Job batchv1.Job `json:"job"`
+}
+
+// JobStrategy installs an operator by running a one-shot installer Job to completion.
+// It's intended for operators whose install logic is too involved to express as a
+// static list of Deployments, e.g. ones that need to run migrations or provisioning
+// scripts before the operator itself can come up.
+type JobStrategy struct {
+	opClient client.Interface
+}
+
+// NewJobStrategy returns a JobStrategy that creates the installer Job via opClient.
+func NewJobStrategy(opClient client.Interface) *JobStrategy {
+	return &JobStrategy{opClient: opClient}
+}
+
+func (j *JobStrategy) Install(ctx context.Context, namespace string, owner metav1.OwnerReference, spec runtime.RawExtension) error {
+	doc, err := parseJobDoc(spec)
+	if err != nil {
+		return err
+	}
+	doc.Job.Namespace = namespace
+	doc.Job.OwnerReferences = append(doc.Job.OwnerReferences, owner)
+	_, err = j.opClient.KubernetesInterface().BatchV1().Jobs(namespace).Create(ctx, &doc.Job, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// An earlier sync already created the installer Job; it may still be running.
+		return nil
+	}
+	return err
+}
+
+func (j *JobStrategy) Uninstall(ctx context.Context, namespace string, spec runtime.RawExtension) error {
+	doc, err := parseJobDoc(spec)
+	if err != nil {
+		return err
+	}
+	propagation := metav1.DeletePropagationBackground
+	return j.opClient.KubernetesInterface().BatchV1().Jobs(namespace).Delete(ctx, doc.Job.Name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+}
+
+// CheckInstalled reports whether the installer Job has run to completion.
+func (j *JobStrategy) CheckInstalled(ctx context.Context, namespace string, spec runtime.RawExtension) (bool, error) {
+	doc, err := parseJobDoc(spec)
+	if err != nil {
+		return false, err
+	}
+	found, err := j.opClient.KubernetesInterface().BatchV1().Jobs(namespace).Get(ctx, doc.Job.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return found.Status.Succeeded > 0, nil
+}
+
+func parseJobDoc(spec runtime.RawExtension) (*jobDoc, error) {
+	doc := &jobDoc{}
+	if err := json.Unmarshal(spec.Raw, doc); err != nil {
+		return nil, errors.Wrap(err, "parsing job install strategy")
+	}
+	return doc, nil
+}
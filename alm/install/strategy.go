@@ -0,0 +1,69 @@
+// Package install defines the pluggable strategies ALM uses to turn an
+// OperatorVersion's InstallStrategy document into running resources.
+package install
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Strategy installs, uninstalls and checks on the health of a single operator version.
+// Implementations are registered into a Registry under the strategy name they handle
+// (the value of the InstallStrategy document's "strategy" field) so that
+// OperatorVersion.Spec.InstallStrategy.strategy can be extended by third parties
+// without patching the controller. Every method takes ctx so a stuck install can be
+// cancelled by shutdown or a per-key deadline instead of blocking a worker forever.
+type Strategy interface {
+	// Install applies whatever resources spec describes into namespace, stamping owner
+	// onto each created object so they can be garbage collected together.
+	Install(ctx context.Context, namespace string, owner metav1.OwnerReference, spec runtime.RawExtension) error
+	// Uninstall removes the resources that a prior Install created.
+	Uninstall(ctx context.Context, namespace string, spec runtime.RawExtension) error
+	// CheckInstalled reports whether the resources described by spec are present and healthy.
+	CheckInstalled(ctx context.Context, namespace string, spec runtime.RawExtension) (bool, error)
+}
+
+// Registry maps strategy names to the Strategy that implements them.
+type Registry struct {
+	strategies map[string]Strategy
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{strategies: map[string]Strategy{}}
+}
+
+// Register adds s under name, overwriting any strategy previously registered under it.
+func (r *Registry) Register(name string, s Strategy) {
+	r.strategies[name] = s
+}
+
+// Get returns the Strategy registered under name, if any.
+func (r *Registry) Get(name string) (Strategy, bool) {
+	s, ok := r.strategies[name]
+	return s, ok
+}
+
+// strategyDoc is the common envelope every InstallStrategy document shares: a
+// "strategy" discriminator plus strategy-specific fields read by each Strategy
+// implementation.
+type strategyDoc struct {
+	Strategy string `json:"strategy"`
+}
+
+// Name extracts the strategy discriminator from an InstallStrategy document.
+func Name(spec runtime.RawExtension) (string, error) {
+	var doc strategyDoc
+	if err := json.Unmarshal(spec.Raw, &doc); err != nil {
+		return "", errors.Wrap(err, "parsing install strategy")
+	}
+	if doc.Strategy == "" {
+		return "", fmt.Errorf("install strategy document missing %q field", "strategy")
+	}
+	return doc.Strategy, nil
+}
@@ -0,0 +1,84 @@
+package install
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRegistryGet(t *testing.T) {
+	registry := NewRegistry()
+
+	if _, ok := registry.Get("deployment"); ok {
+		t.Fatalf("Get returned a strategy before any were registered")
+	}
+
+	registry.Register("deployment", NewDeploymentStrategy(nil))
+	strategy, ok := registry.Get("deployment")
+	if !ok {
+		t.Fatalf("Get failed to find a registered strategy")
+	}
+	if strategy == nil {
+		t.Fatalf("Get returned a nil strategy for a registered name")
+	}
+}
+
+func TestRegistryRegisterOverwrites(t *testing.T) {
+	registry := NewRegistry()
+	first := NewDeploymentStrategy(nil)
+	second := NewDeploymentStrategy(nil)
+
+	registry.Register("deployment", first)
+	registry.Register("deployment", second)
+
+	got, ok := registry.Get("deployment")
+	if !ok {
+		t.Fatalf("Get failed to find the overwriting strategy")
+	}
+	if got != second {
+		t.Fatalf("Register did not overwrite the strategy previously registered under the name")
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "strategy present",
+			raw:  `{"strategy":"helm","chart":"abc"}`,
+			want: "helm",
+		},
+		{
+			name:    "strategy missing",
+			raw:     `{"chart":"abc"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			raw:     `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Name(runtime.RawExtension{Raw: []byte(test.raw)})
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Name(%q) returned nil error, want one", test.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Name(%q) returned unexpected error: %v", test.raw, err)
+			}
+			if got != test.want {
+				t.Fatalf("Name(%q) = %q, want %q", test.raw, got, test.want)
+			}
+		})
+	}
+}
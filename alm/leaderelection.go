@@ -0,0 +1,105 @@
+package alm
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaderElectionConfig controls whether and how an Operator coordinates with other replicas
+// of itself over a shared Lease before starting the informer and workers, so that exactly
+// one replica is ever reconciling OperatorVersions at a time.
+type LeaderElectionConfig struct {
+	// Enabled turns on leader election. When false, Run starts the informer and workers
+	// immediately, as if this replica always held the lease.
+	Enabled bool
+
+	// LockName and LockNamespace identify the Lease replicas contend for.
+	LockName      string
+	LockNamespace string
+
+	// Identity is this replica's holder identity in the Lease; it must be unique across
+	// replicas. Defaults to the host's hostname if empty.
+	Identity string
+
+	// LeaseDuration, RenewDeadline and RetryPeriod tune how quickly a dead leader's lease
+	// is reclaimed versus how much load leader election adds; see
+	// k8s.io/client-go/tools/leaderelection.LeaderElectionConfig for their exact semantics.
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// withLeaderElectionDefaults fills in any zero-valued lease tuning fields of cfg with the
+// defaults client-go's own leaderelection package recommends, leaving cfg.Enabled and any
+// fields the caller already set untouched.
+func withLeaderElectionDefaults(cfg LeaderElectionConfig) LeaderElectionConfig {
+	if cfg.LockName == "" {
+		cfg.LockName = "alm-operator-lock"
+	}
+	if cfg.LockNamespace == "" {
+		cfg.LockNamespace = "default"
+	}
+	if cfg.LeaseDuration == 0 {
+		cfg.LeaseDuration = 15 * time.Second
+	}
+	if cfg.RenewDeadline == 0 {
+		cfg.RenewDeadline = 10 * time.Second
+	}
+	if cfg.RetryPeriod == 0 {
+		cfg.RetryPeriod = 2 * time.Second
+	}
+	return cfg
+}
+
+// runWithLeaderElection blocks until ctx is cancelled or this replica loses leadership,
+// starting the informer and workers only while it holds the lease.
+func (o *Operator) runWithLeaderElection(ctx context.Context) error {
+	identity := o.leaderElection.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "determining leader election identity")
+		}
+		identity = hostname
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		o.leaderElection.LockNamespace,
+		o.leaderElection.LockName,
+		o.opClient.KubernetesInterface().CoreV1(),
+		o.opClient.KubernetesInterface().CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return errors.Wrap(err, "creating leader election lock")
+	}
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	leaderelection.RunOrDie(leaderCtx, leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: o.leaderElection.LeaseDuration,
+		RenewDeadline: o.leaderElection.RenewDeadline,
+		RetryPeriod:   o.leaderElection.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Info("msg", "started leading", "identity", identity)
+				o.runWorkers(ctx)
+			},
+			OnStoppedLeading: func() {
+				log.Info("msg", "stopped leading", "identity", identity)
+				cancel()
+			},
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package alm
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// operatorVersionAPIVersion and operatorVersionKind are the GVK stamped onto every
+// OwnerReference ownerRef builds. They can't be read off ov.TypeMeta: objects delivered
+// by the SharedIndexInformer never have their TypeMeta populated, so reading it here
+// would produce an empty apiVersion/kind the API server rejects on Create.
+const (
+	operatorVersionAPIVersion = "alm.coreos.com/v1alpha1"
+	operatorVersionKind       = "OperatorVersion"
+)
+
+// ownerRef returns an OwnerReference pointing at ov, suitable for stamping onto every
+// resource an install strategy creates so they're garbage collected when ov is deleted.
+func ownerRef(ov *OperatorVersion) metav1.OwnerReference {
+	controller := true
+	blockOwnerDeletion := true
+	return metav1.OwnerReference{
+		APIVersion:         operatorVersionAPIVersion,
+		Kind:               operatorVersionKind,
+		Name:               ov.ObjectMeta.Name,
+		UID:                ov.ObjectMeta.UID,
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
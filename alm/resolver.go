@@ -0,0 +1,143 @@
+package alm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// apiGVK identifies the API a requirement or a provided entry refers to.
+type apiGVK struct {
+	Group, Version, Kind string
+}
+
+func (r APIRequirement) gvk() apiGVK { return apiGVK{r.Group, r.Version, r.Kind} }
+func (p APIProvided) gvk() apiGVK    { return apiGVK{p.Group, p.Version, p.Kind} }
+
+// versionLabel is the well-known label an OperatorVersion's own semver is read from when
+// checking a requirement's VersionRange.
+const versionLabel = "alm.coreos.com/version"
+
+// resolution is the outcome of resolving one OperatorVersion's dependencies.
+type resolution struct {
+	satisfied bool
+	missing   []string
+}
+
+// dependencyCycleError is returned when resolving an OperatorVersion's dependencies finds
+// a cycle in the Requires/Provides graph.
+type dependencyCycleError struct {
+	cycle []string
+}
+
+func (e *dependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.cycle, " -> "))
+}
+
+// resolveDependencies decides whether every API ov.Spec.Requires is Provided by some other
+// OperatorVersion that has already installed successfully, and detects dependency cycles
+// across every OperatorVersion reachable from ov. It only considers OperatorVersions in
+// ov's own namespace plus any cluster-scoped ones (empty namespace).
+func (o *Operator) resolveDependencies(ov *OperatorVersion) (resolution, error) {
+	candidates := o.listOperatorVersions(ov.ObjectMeta.Namespace)
+
+	providers := map[apiGVK][]*OperatorVersion{}
+	for _, candidate := range candidates {
+		for _, p := range candidate.Spec.Provides {
+			providers[p.gvk()] = append(providers[p.gvk()], candidate)
+		}
+	}
+
+	if cycle := findDependencyCycle(ov, providers); len(cycle) > 0 {
+		return resolution{}, &dependencyCycleError{cycle: cycle}
+	}
+
+	var missing []string
+	for _, req := range ov.Spec.Requires {
+		if !requirementSatisfied(req, providers[req.gvk()]) {
+			missing = append(missing, fmt.Sprintf("%s/%s, Kind=%s", req.Group, req.Version, req.Kind))
+		}
+	}
+	return resolution{satisfied: len(missing) == 0, missing: missing}, nil
+}
+
+// requirementSatisfied reports whether any candidate providing req has already installed
+// successfully and, if req constrains the provider's version, falls within that range. A
+// candidate that constrains VersionRange but is missing (or has an unparseable)
+// versionLabel is treated as not satisfying req, not as an unconstrained match: the label
+// is how a provider advertises the version a VersionRange is checked against, and silently
+// accepting an unlabeled provider would let a requirer depend on a version it never agreed to.
+func requirementSatisfied(req APIRequirement, candidates []*OperatorVersion) bool {
+	for _, candidate := range candidates {
+		if candidate.Status.Phase != PhaseSucceeded {
+			continue
+		}
+		if req.VersionRange == "" {
+			return true
+		}
+		constraint, err := semver.ParseRange(req.VersionRange)
+		if err != nil {
+			continue
+		}
+		version, err := semver.Parse(candidate.ObjectMeta.Labels[versionLabel])
+		if err != nil {
+			continue
+		}
+		if constraint(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDependencyCycle walks ov's Requires edges through providers and returns the chain of
+// "namespace/name" keys forming a cycle, or nil if the graph reachable from ov is acyclic.
+func findDependencyCycle(ov *OperatorVersion, providers map[apiGVK][]*OperatorVersion) []string {
+	visiting := map[string]bool{}
+	visited := map[string]bool{}
+	var path []string
+
+	var visit func(n *OperatorVersion) []string
+	visit = func(n *OperatorVersion) []string {
+		key := n.ObjectMeta.Namespace + "/" + n.ObjectMeta.Name
+		if visiting[key] {
+			return append(append([]string{}, path...), key)
+		}
+		if visited[key] {
+			return nil
+		}
+
+		visiting[key] = true
+		path = append(path, key)
+		for _, req := range n.Spec.Requires {
+			for _, provider := range providers[req.gvk()] {
+				if cycle := visit(provider); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		visiting[key] = false
+		visited[key] = true
+		return nil
+	}
+
+	return visit(ov)
+}
+
+// listOperatorVersions returns every cached OperatorVersion in namespace plus any
+// cluster-scoped ones, for the resolver to consider as potential dependency providers.
+func (o *Operator) listOperatorVersions(namespace string) []*OperatorVersion {
+	var result []*OperatorVersion
+	for _, obj := range o.informer.GetIndexer().List() {
+		ov, ok := obj.(*OperatorVersion)
+		if !ok {
+			continue
+		}
+		if ov.ObjectMeta.Namespace == namespace || ov.ObjectMeta.Namespace == "" {
+			result = append(result, ov)
+		}
+	}
+	return result
+}
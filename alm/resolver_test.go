@@ -0,0 +1,87 @@
+package alm
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func operatorVersion(namespace, name string, phase Phase, requires []APIRequirement, provides []APIProvided) *OperatorVersion {
+	return &OperatorVersion{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       OperatorVersionSpec{Requires: requires, Provides: provides},
+		Status:     OperatorVersionStatus{Phase: phase},
+	}
+}
+
+func TestRequirementSatisfied(t *testing.T) {
+	etcdGVK := APIProvided{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}
+	req := APIRequirement{Group: etcdGVK.Group, Version: etcdGVK.Version, Kind: etcdGVK.Kind}
+
+	notInstalled := operatorVersion("ns", "etcd-operator", PhasePending, nil, []APIProvided{etcdGVK})
+	if requirementSatisfied(req, []*OperatorVersion{notInstalled}) {
+		t.Fatalf("requirementSatisfied() reported satisfied by a provider that hasn't Succeeded")
+	}
+
+	installed := operatorVersion("ns", "etcd-operator", PhaseSucceeded, nil, []APIProvided{etcdGVK})
+	if !requirementSatisfied(req, []*OperatorVersion{installed}) {
+		t.Fatalf("requirementSatisfied() reported unsatisfied despite a Succeeded, unconstrained provider")
+	}
+}
+
+func TestRequirementSatisfiedVersionRange(t *testing.T) {
+	etcdGVK := APIProvided{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}
+	req := APIRequirement{Group: etcdGVK.Group, Version: etcdGVK.Version, Kind: etcdGVK.Kind, VersionRange: ">=3.0.0 <4.0.0"}
+
+	withinRange := operatorVersion("ns", "etcd-operator", PhaseSucceeded, nil, []APIProvided{etcdGVK})
+	withinRange.ObjectMeta.Labels = map[string]string{versionLabel: "3.2.1"}
+	if !requirementSatisfied(req, []*OperatorVersion{withinRange}) {
+		t.Fatalf("requirementSatisfied() reported unsatisfied for a provider version within range")
+	}
+
+	outsideRange := operatorVersion("ns", "etcd-operator", PhaseSucceeded, nil, []APIProvided{etcdGVK})
+	outsideRange.ObjectMeta.Labels = map[string]string{versionLabel: "4.0.0"}
+	if requirementSatisfied(req, []*OperatorVersion{outsideRange}) {
+		t.Fatalf("requirementSatisfied() reported satisfied for a provider version outside range")
+	}
+
+	missingLabel := operatorVersion("ns", "etcd-operator", PhaseSucceeded, nil, []APIProvided{etcdGVK})
+	if requirementSatisfied(req, []*OperatorVersion{missingLabel}) {
+		t.Fatalf("requirementSatisfied() reported satisfied for a provider missing %s", versionLabel)
+	}
+}
+
+func TestFindDependencyCycleAcyclic(t *testing.T) {
+	aGVK := APIProvided{Group: "a.coreos.com", Version: "v1", Kind: "A"}
+	bGVK := APIProvided{Group: "b.coreos.com", Version: "v1", Kind: "B"}
+
+	a := operatorVersion("ns", "a", PhaseSucceeded, []APIRequirement{{Group: bGVK.Group, Version: bGVK.Version, Kind: bGVK.Kind}}, []APIProvided{aGVK})
+	b := operatorVersion("ns", "b", PhaseSucceeded, nil, []APIProvided{bGVK})
+
+	providers := map[apiGVK][]*OperatorVersion{
+		aGVK.gvk(): {a},
+		bGVK.gvk(): {b},
+	}
+
+	if cycle := findDependencyCycle(a, providers); len(cycle) != 0 {
+		t.Fatalf("findDependencyCycle() found a cycle in an acyclic graph: %v", cycle)
+	}
+}
+
+func TestFindDependencyCycleDetectsCycle(t *testing.T) {
+	aGVK := APIProvided{Group: "a.coreos.com", Version: "v1", Kind: "A"}
+	bGVK := APIProvided{Group: "b.coreos.com", Version: "v1", Kind: "B"}
+
+	a := operatorVersion("ns", "a", PhaseSucceeded, []APIRequirement{{Group: bGVK.Group, Version: bGVK.Version, Kind: bGVK.Kind}}, []APIProvided{aGVK})
+	b := operatorVersion("ns", "b", PhaseSucceeded, []APIRequirement{{Group: aGVK.Group, Version: aGVK.Version, Kind: aGVK.Kind}}, []APIProvided{bGVK})
+
+	providers := map[apiGVK][]*OperatorVersion{
+		aGVK.gvk(): {a},
+		bGVK.gvk(): {b},
+	}
+
+	cycle := findDependencyCycle(a, providers)
+	if len(cycle) == 0 {
+		t.Fatalf("findDependencyCycle() missed the a -> b -> a cycle")
+	}
+}
@@ -0,0 +1,37 @@
+package alm
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// operatorVersionGroupVersion is the operatorversions CRD's own group/version (see
+// operatorVersionAPIVersion in owner.go). It's distinct from the built-in Kubernetes APIs
+// opClient.KubernetesInterface() talks to, so writes to OperatorVersion need a REST client
+// scoped to this group/version rather than, say, CoreV1().RESTClient(), which is bound to
+// /api/v1 and would 404 against operatorversions.
+var operatorVersionGroupVersion = schema.GroupVersion{Group: "alm.coreos.com", Version: "v1alpha1"}
+
+// newOperatorVersionClient returns a REST client scoped to operatorVersionGroupVersion,
+// built from the same kubeconfig (or in-cluster config, if empty) used for the Kubernetes
+// clientset, for the finalizer and status writes in finalizer.go and status.go.
+func newOperatorVersionClient(kubeconfig string) (rest.Interface, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypes(operatorVersionGroupVersion, &OperatorVersion{}, &OperatorVersionList{})
+	metav1.AddToGroupVersion(scheme, operatorVersionGroupVersion)
+
+	config.GroupVersion = &operatorVersionGroupVersion
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = serializer.NewCodecFactory(scheme)
+
+	return rest.RESTClientFor(config)
+}
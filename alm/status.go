@@ -0,0 +1,194 @@
+package alm
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/coreos-inc/alm/install"
+	"github.com/pkg/errors"
+)
+
+// Phase is a coarse, human-readable summary of where an OperatorVersion is in its
+// install lifecycle.
+type Phase string
+
+const (
+	PhasePending    Phase = "Pending"
+	PhaseInstalling Phase = "Installing"
+	PhaseSucceeded  Phase = "Succeeded"
+	PhaseFailed     Phase = "Failed"
+)
+
+// ConditionType enumerates the conditions ALM reports on an OperatorVersion.
+type ConditionType string
+
+const (
+	// ConditionInstalled reports whether the install strategy's resources are present and ready.
+	ConditionInstalled ConditionType = "Installed"
+	// ConditionResolved reports whether every API this OperatorVersion Requires is
+	// Provided by an already-installed OperatorVersion.
+	ConditionResolved ConditionType = "Resolved"
+)
+
+// Condition is a single timestamped observation about an OperatorVersion, mirroring the
+// Type/Status/Reason/Message/LastTransitionTime shape used by operatorv1.OperatorStatus in
+// the openshift/library-go controllers.
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+}
+
+// OperatorVersionStatus is the observed state of an OperatorVersion's install, computed by
+// the controller and never set by a caller.
+type OperatorVersionStatus struct {
+	Phase      Phase       `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// reconcileStatus computes the status ov should report given the outcome of this sync,
+// folds in the Resolved condition (sync only reaches here once resolveDependencies reports
+// ov satisfied), and, if the result differs from ov.Status, PATCHes the status subresource
+// once. installErr is the error (if any) strategy.Install just returned for ov.
+func (o *Operator) reconcileStatus(ctx context.Context, ov *OperatorVersion, strategy install.Strategy, installErr error) error {
+	desired := o.computeStatus(ctx, ov, strategy, installErr)
+	desired.Conditions = setCondition(desired.Conditions, Condition{
+		Type:    ConditionResolved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "DependenciesSatisfied",
+		Message: "all required APIs are provided and installed",
+	})
+	if statusEqual(ov.Status, desired) {
+		return nil
+	}
+	if err := o.patchStatus(ctx, ov, desired); err != nil {
+		return err
+	}
+	ov.Status = desired
+	return nil
+}
+
+// setResolvedCondition reports the outcome of resolveDependencies on ov's ConditionResolved
+// condition, PATCHing the status subresource if it changed ov's reported status.
+func (o *Operator) setResolvedCondition(ctx context.Context, ov *OperatorVersion, phase Phase, status corev1.ConditionStatus, reason, message string) error {
+	desired := withCondition(ov.Status, phase, Condition{
+		Type:    ConditionResolved,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	if statusEqual(ov.Status, desired) {
+		return nil
+	}
+	if err := o.patchStatus(ctx, ov, desired); err != nil {
+		return err
+	}
+	ov.Status = desired
+	return nil
+}
+
+func (o *Operator) computeStatus(ctx context.Context, ov *OperatorVersion, strategy install.Strategy, installErr error) OperatorVersionStatus {
+	if installErr != nil {
+		return withCondition(ov.Status, PhaseFailed, Condition{
+			Type:    ConditionInstalled,
+			Status:  corev1.ConditionFalse,
+			Reason:  "InstallFailed",
+			Message: installErr.Error(),
+		})
+	}
+
+	installed, err := strategy.CheckInstalled(ctx, ov.ObjectMeta.Namespace, ov.Spec.InstallStrategy)
+	if err != nil {
+		return withCondition(ov.Status, PhaseInstalling, Condition{
+			Type:    ConditionInstalled,
+			Status:  corev1.ConditionUnknown,
+			Reason:  "CheckInstalledFailed",
+			Message: err.Error(),
+		})
+	}
+	if !installed {
+		return withCondition(ov.Status, PhaseInstalling, Condition{
+			Type:    ConditionInstalled,
+			Status:  corev1.ConditionFalse,
+			Reason:  "WaitingForDeployments",
+			Message: "waiting for installed resources to become ready",
+		})
+	}
+	return withCondition(ov.Status, PhaseSucceeded, Condition{
+		Type:    ConditionInstalled,
+		Status:  corev1.ConditionTrue,
+		Reason:  "InstallSucceeded",
+		Message: "install strategy reports all resources ready",
+	})
+}
+
+// withCondition returns the status ov should move to: phase plus cond upserted into
+// current's conditions by Type, reusing cond's LastTransitionTime from current if a
+// condition of the same Type and Status is already present, and leaving every other
+// condition Type (e.g. Resolved, set by the dependency resolver) untouched.
+func withCondition(current OperatorVersionStatus, phase Phase, cond Condition) OperatorVersionStatus {
+	return OperatorVersionStatus{Phase: phase, Conditions: setCondition(current.Conditions, cond)}
+}
+
+// setCondition returns a copy of conditions with cond upserted by Type, preserving
+// LastTransitionTime when cond's Status matches the existing condition of the same Type.
+func setCondition(conditions []Condition, cond Condition) []Condition {
+	cond.LastTransitionTime = metav1.Now()
+	out := make([]Condition, 0, len(conditions)+1)
+	found := false
+	for _, existing := range conditions {
+		if existing.Type != cond.Type {
+			out = append(out, existing)
+			continue
+		}
+		if existing.Status == cond.Status {
+			cond.LastTransitionTime = existing.LastTransitionTime
+		}
+		out = append(out, cond)
+		found = true
+	}
+	if !found {
+		out = append(out, cond)
+	}
+	return out
+}
+
+// statusEqual compares two statuses ignoring LastTransitionTime, so an unchanged status
+// doesn't trigger a PATCH (and re-enqueue the update handler would otherwise cause).
+func statusEqual(a, b OperatorVersionStatus) bool {
+	if a.Phase != b.Phase || len(a.Conditions) != len(b.Conditions) {
+		return false
+	}
+	for i := range a.Conditions {
+		if a.Conditions[i].Type != b.Conditions[i].Type ||
+			a.Conditions[i].Status != b.Conditions[i].Status ||
+			a.Conditions[i].Reason != b.Conditions[i].Reason ||
+			a.Conditions[i].Message != b.Conditions[i].Message {
+			return false
+		}
+	}
+	return true
+}
+
+// patchStatus writes status to ov's status subresource via a JSON merge patch.
+func (o *Operator) patchStatus(ctx context.Context, ov *OperatorVersion, status OperatorVersionStatus) error {
+	patch, err := json.Marshal(map[string]OperatorVersionStatus{"status": status})
+	if err != nil {
+		return errors.Wrap(err, "encoding status patch")
+	}
+	return o.operatorVersionClient.
+		Patch(types.MergePatchType).
+		Namespace(ov.ObjectMeta.Namespace).
+		Resource("operatorversions").
+		Name(ov.ObjectMeta.Name).
+		SubResource("status").
+		Body(patch).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,78 @@
+package alm
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetConditionUpsertsByType(t *testing.T) {
+	existing := []Condition{
+		{Type: ConditionResolved, Status: corev1.ConditionTrue, Reason: "DependenciesSatisfied"},
+	}
+
+	updated := setCondition(existing, Condition{Type: ConditionInstalled, Status: corev1.ConditionTrue, Reason: "InstallSucceeded"})
+	if len(updated) != 2 {
+		t.Fatalf("setCondition() did not append a condition of a new Type, got %d conditions", len(updated))
+	}
+
+	replaced := setCondition(updated, Condition{Type: ConditionResolved, Status: corev1.ConditionFalse, Reason: "WaitingForDependencies"})
+	if len(replaced) != 2 {
+		t.Fatalf("setCondition() should replace an existing Type in place, got %d conditions", len(replaced))
+	}
+	for _, c := range replaced {
+		if c.Type == ConditionResolved && c.Reason != "WaitingForDependencies" {
+			t.Fatalf("setCondition() did not overwrite the existing Resolved condition, got reason %q", c.Reason)
+		}
+	}
+}
+
+func TestSetConditionPreservesLastTransitionTimeWhenStatusUnchanged(t *testing.T) {
+	transitioned := metav1.NewTime(metav1.Now().Add(-1))
+	existing := []Condition{
+		{Type: ConditionInstalled, Status: corev1.ConditionTrue, LastTransitionTime: transitioned},
+	}
+
+	same := setCondition(existing, Condition{Type: ConditionInstalled, Status: corev1.ConditionTrue})
+	if !same[0].LastTransitionTime.Equal(&transitioned) {
+		t.Fatalf("setCondition() updated LastTransitionTime for an unchanged Status")
+	}
+
+	changed := setCondition(existing, Condition{Type: ConditionInstalled, Status: corev1.ConditionFalse})
+	if changed[0].LastTransitionTime.Equal(&transitioned) {
+		t.Fatalf("setCondition() kept the stale LastTransitionTime after a Status change")
+	}
+}
+
+func TestStatusEqual(t *testing.T) {
+	base := OperatorVersionStatus{
+		Phase: PhaseSucceeded,
+		Conditions: []Condition{
+			{Type: ConditionInstalled, Status: corev1.ConditionTrue, Reason: "InstallSucceeded", LastTransitionTime: metav1.Now()},
+		},
+	}
+	sameButNewerTimestamp := OperatorVersionStatus{
+		Phase: PhaseSucceeded,
+		Conditions: []Condition{
+			{Type: ConditionInstalled, Status: corev1.ConditionTrue, Reason: "InstallSucceeded", LastTransitionTime: metav1.NewTime(metav1.Now().Add(1))},
+		},
+	}
+	if !statusEqual(base, sameButNewerTimestamp) {
+		t.Fatalf("statusEqual() treated a LastTransitionTime-only difference as a change")
+	}
+
+	differentPhase := sameButNewerTimestamp
+	differentPhase.Phase = PhaseFailed
+	if statusEqual(base, differentPhase) {
+		t.Fatalf("statusEqual() missed a Phase difference")
+	}
+
+	differentReason := base
+	differentReason.Conditions = []Condition{
+		{Type: ConditionInstalled, Status: corev1.ConditionTrue, Reason: "CheckInstalledFailed"},
+	}
+	if statusEqual(base, differentReason) {
+		t.Fatalf("statusEqual() missed a Reason difference")
+	}
+}
@@ -0,0 +1,99 @@
+package alm
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// OperatorVersion describes a single version of an operator that ALM knows how to install.
+type OperatorVersion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorVersionSpec   `json:"spec"`
+	Status OperatorVersionStatus `json:"status,omitempty"`
+}
+
+// OperatorVersionSpec describes how to install a particular operator version.
+type OperatorVersionSpec struct {
+	// InstallStrategy is the strategy-specific configuration used to install the operator,
+	// e.g. {"strategy": "deployment", "deployments": [...]}.
+	InstallStrategy runtime.RawExtension `json:"installStrategy"`
+
+	// Requires lists the APIs that must already be Provided (and installed) by another
+	// OperatorVersion before this one can be installed.
+	Requires []APIRequirement `json:"requires,omitempty"`
+	// Provides lists the APIs this operator version makes available to other
+	// OperatorVersions that Require them.
+	Provides []APIProvided `json:"provides,omitempty"`
+}
+
+// APIRequirement identifies an API GroupVersionKind an OperatorVersion depends on, and
+// optionally constrains which provider versions satisfy it.
+type APIRequirement struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	// VersionRange is a semver range (e.g. ">=1.0.0 <2.0.0") the providing
+	// OperatorVersion's own version must satisfy. Empty means any provider will do.
+	VersionRange string `json:"versionRange,omitempty"`
+}
+
+// APIProvided identifies an API GroupVersionKind an OperatorVersion makes available.
+type APIProvided struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// OperatorVersionList is a list of OperatorVersion resources.
+type OperatorVersionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []OperatorVersion `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object so OperatorVersion can be used with informers.
+func (in *OperatorVersion) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorVersion)
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.InstallStrategy.DeepCopyInto(&out.Spec.InstallStrategy)
+	if in.Spec.Requires != nil {
+		out.Spec.Requires = make([]APIRequirement, len(in.Spec.Requires))
+		copy(out.Spec.Requires, in.Spec.Requires)
+	}
+	if in.Spec.Provides != nil {
+		out.Spec.Provides = make([]APIProvided, len(in.Spec.Provides))
+		copy(out.Spec.Provides, in.Spec.Provides)
+	}
+	out.Status.Phase = in.Status.Phase
+	if in.Status.Conditions != nil {
+		out.Status.Conditions = make([]Condition, len(in.Status.Conditions))
+		copy(out.Status.Conditions, in.Status.Conditions)
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object so OperatorVersionList can be used with informers.
+func (in *OperatorVersionList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorVersionList)
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]OperatorVersion, len(in.Items))
+		for i := range in.Items {
+			out.Items[i] = *in.Items[i].DeepCopyObject().(*OperatorVersion)
+		}
+	}
+	return out
+}
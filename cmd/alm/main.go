@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/coreos-inc/alm"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	var (
+		kubeconfig = flag.String("kubeconfig", "", "path to a kubeconfig; if empty, in-cluster config is used")
+		workers    = flag.Int("workers", 0, "number of OperatorVersions to reconcile concurrently; defaults to 1")
+
+		leaderElect              = flag.Bool("leader-elect", false, "run with leader election, so multiple replicas can be started HA")
+		leaderElectLockName      = flag.String("leader-elect-lock-name", "alm-operator-lock", "name of the lease replicas elect a leader over")
+		leaderElectLockNamespace = flag.String("leader-elect-lock-namespace", "default", "namespace of the lease replicas elect a leader over")
+		leaderElectLeaseDuration = flag.Duration("leader-elect-lease-duration", 15*time.Second, "duration non-leader replicas wait before attempting to become leader")
+		leaderElectRenewDeadline = flag.Duration("leader-elect-renew-deadline", 10*time.Second, "duration the leader retries refreshing leadership before giving it up")
+		leaderElectRetryPeriod   = flag.Duration("leader-elect-retry-period", 2*time.Second, "duration clients wait between actions in the leader election loop")
+	)
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	operator, err := alm.New(*kubeconfig, *workers, alm.LeaderElectionConfig{
+		Enabled:       *leaderElect,
+		LockName:      *leaderElectLockName,
+		LockNamespace: *leaderElectLockNamespace,
+		LeaseDuration: *leaderElectLeaseDuration,
+		RenewDeadline: *leaderElectRenewDeadline,
+		RetryPeriod:   *leaderElectRetryPeriod,
+	})
+	if err != nil {
+		log.Fatal("msg", "creating operator failed", "err", err)
+	}
+
+	if err := operator.Run(ctx); err != nil {
+		log.Fatal("msg", "operator exited with error", "err", err)
+	}
+}
@@ -0,0 +1,62 @@
+package operators
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/coreos-inc/operator-client/pkg/client"
+)
+
+// KubeDeployment installs an operator by applying one or more Deployment manifests directly.
+type KubeDeployment struct {
+	opClient client.Interface
+}
+
+// NewKubeDeployment returns a KubeDeployment that creates resources via opClient.
+func NewKubeDeployment(opClient client.Interface) *KubeDeployment {
+	return &KubeDeployment{opClient: opClient}
+}
+
+// Install creates each of the given Deployments in namespace, owned by owner so they're
+// garbage collected together. A Deployment that already exists is left as it is.
+func (k *KubeDeployment) Install(ctx context.Context, namespace string, owner metav1.OwnerReference, deployments []appsv1.Deployment) error {
+	for _, d := range deployments {
+		d.Namespace = namespace
+		d.OwnerReferences = append(d.OwnerReferences, owner)
+		if _, err := k.opClient.KubernetesInterface().AppsV1().Deployments(namespace).Create(ctx, &d, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Uninstall deletes each of the given Deployments from namespace. A Deployment that is
+// already gone is skipped, so an Uninstall that stopped part way can be run again.
+func (k *KubeDeployment) Uninstall(ctx context.Context, namespace string, deployments []appsv1.Deployment) error {
+	for _, d := range deployments {
+		if err := k.opClient.KubernetesInterface().AppsV1().Deployments(namespace).Delete(ctx, d.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckInstalled reports whether every named Deployment exists and has at least one ready replica.
+func (k *KubeDeployment) CheckInstalled(ctx context.Context, namespace string, deployments []appsv1.Deployment) (bool, error) {
+	for _, d := range deployments {
+		found, err := k.opClient.KubernetesInterface().AppsV1().Deployments(namespace).Get(ctx, d.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if found.Status.ReadyReplicas < 1 {
+			return false, nil
+		}
+	}
+	return true, nil
+}